@@ -0,0 +1,83 @@
+// Package activity scores how "recently active" a branch is from a set
+// of raw git signals, with weights a user can tune instead of grecent
+// hard-coding max(committerdate, reflog, upstream tip) as it used to.
+package activity
+
+import "time"
+
+// Signals are the raw per-branch observations a score is computed from.
+// Has* flags distinguish "never happened" from a zero time.Time, since a
+// branch with no reflog or no upstream shouldn't be scored as if it were
+// last touched at the Unix epoch.
+type Signals struct {
+	CommitterDate     time.Time
+	AuthorDate        time.Time
+	ReflogTime        time.Time
+	HasReflog         bool
+	UpstreamTipDate   time.Time
+	HasUpstreamTip    bool
+	ReflogCountRecent int
+	IsHead            bool
+}
+
+// Weights control how much each signal contributes to a branch's
+// composite activity score. Configure via ~/.config/grecent/config.toml's
+// [activity] table; DefaultWeights applies to anything left unset.
+type Weights struct {
+	CommitterDateWeight float64
+	AuthorDateWeight    float64
+	ReflogWeight        float64
+	UpstreamTipWeight   float64
+	ReflogCountWeight   float64
+	HeadWeight          float64
+	RecentReflogWindow  time.Duration
+}
+
+// DefaultWeights favours reflog activity - what you actually worked on
+// locally - over committer date, which a rebase or amend can rewrite
+// without you having touched the branch recently, plus a flat bonus for
+// whichever branch is currently checked out.
+func DefaultWeights() Weights {
+	return Weights{
+		CommitterDateWeight: 1.0,
+		AuthorDateWeight:    0.5,
+		ReflogWeight:        2.0,
+		UpstreamTipWeight:   1.0,
+		ReflogCountWeight:   0.1,
+		HeadWeight:          5.0,
+		RecentReflogWindow:  7 * 24 * time.Hour,
+	}
+}
+
+// Score combines s's signals into a single number, higher meaning more
+// recently active. Each timestamp signal contributes its weight scaled
+// by recency (1 / (1 + age in days)) rather than raw Unix time, so one
+// very old signal can't swamp the others just by having a bigger magnitude.
+func Score(s Signals, w Weights, now time.Time) float64 {
+	score := w.CommitterDateWeight * recency(s.CommitterDate, now)
+	if !s.AuthorDate.IsZero() {
+		score += w.AuthorDateWeight * recency(s.AuthorDate, now)
+	}
+	if s.HasReflog {
+		score += w.ReflogWeight * recency(s.ReflogTime, now)
+	}
+	if s.HasUpstreamTip {
+		score += w.UpstreamTipWeight * recency(s.UpstreamTipDate, now)
+	}
+	score += w.ReflogCountWeight * float64(s.ReflogCountRecent)
+	if s.IsHead {
+		score += w.HeadWeight
+	}
+	return score
+}
+
+func recency(t time.Time, now time.Time) float64 {
+	if t.IsZero() {
+		return 0
+	}
+	days := now.Sub(t).Hours() / 24
+	if days < 0 {
+		days = 0
+	}
+	return 1 / (1 + days)
+}
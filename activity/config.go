@@ -0,0 +1,93 @@
+package activity
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// fileConfig mirrors config.toml's [activity] table. Pointer fields
+// distinguish "not set" from "set to zero", so a partial config only
+// overrides the weights it mentions.
+type fileConfig struct {
+	Activity struct {
+		CommitterDateWeight *float64 `toml:"committer_date_weight"`
+		AuthorDateWeight    *float64 `toml:"author_date_weight"`
+		ReflogWeight        *float64 `toml:"reflog_weight"`
+		UpstreamTipWeight   *float64 `toml:"upstream_tip_weight"`
+		ReflogCountWeight   *float64 `toml:"reflog_count_weight"`
+		HeadWeight          *float64 `toml:"head_weight"`
+		RecentReflogWindow  string   `toml:"recent_reflog_window"`
+	} `toml:"activity"`
+}
+
+// ConfigPath returns the path grecent reads activity weights from:
+// ~/.config/grecent/config.toml.
+func ConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "grecent", "config.toml"), nil
+}
+
+// LoadWeights reads ~/.config/grecent/config.toml, falling back to
+// DefaultWeights for any field left unset, or entirely if the file
+// doesn't exist.
+func LoadWeights() (Weights, error) {
+	w := DefaultWeights()
+
+	path, err := ConfigPath()
+	if err != nil {
+		return w, err
+	}
+
+	var cfg fileConfig
+	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+		if os.IsNotExist(err) {
+			return w, nil
+		}
+		return w, err
+	}
+
+	a := cfg.Activity
+	if a.CommitterDateWeight != nil {
+		w.CommitterDateWeight = *a.CommitterDateWeight
+	}
+	if a.AuthorDateWeight != nil {
+		w.AuthorDateWeight = *a.AuthorDateWeight
+	}
+	if a.ReflogWeight != nil {
+		w.ReflogWeight = *a.ReflogWeight
+	}
+	if a.UpstreamTipWeight != nil {
+		w.UpstreamTipWeight = *a.UpstreamTipWeight
+	}
+	if a.ReflogCountWeight != nil {
+		w.ReflogCountWeight = *a.ReflogCountWeight
+	}
+	if a.HeadWeight != nil {
+		w.HeadWeight = *a.HeadWeight
+	}
+	if a.RecentReflogWindow != "" {
+		d, err := parseWindow(a.RecentReflogWindow)
+		if err != nil {
+			return w, err
+		}
+		w.RecentReflogWindow = d
+	}
+	return w, nil
+}
+
+// parseWindow accepts Go durations plus a bare "<N>d" day suffix, since
+// "7d" reads more naturally than "168h" in a config file.
+func parseWindow(s string) (time.Duration, error) {
+	if len(s) > 1 && s[len(s)-1] == 'd' {
+		if d, err := time.ParseDuration(s[:len(s)-1] + "h"); err == nil {
+			return d * 24, nil
+		}
+	}
+	return time.ParseDuration(s)
+}
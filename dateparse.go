@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/tj/go-naturaldate"
+)
+
+// relativeDateExprPattern matches the natural-language expressions
+// naturaldate's grammar actually assigns meaning to (see its grammar.peg):
+// relative units, weekday/month names and clock times. naturaldate's
+// design goal is free-text extraction ("Restart the server in 5 days"),
+// so unrecognized words parse as silent no-ops rather than errors -
+// Parse("banana", ...) happily returns now with a nil error. That's fine
+// for log-search text but not for a --since/--until value, so we gate
+// naturaldate on this allowlist first and treat anything it doesn't
+// match as the clear, user-facing error the raw library can't give us.
+var relativeDateExprPattern = regexp.MustCompile(`(?i)^(` +
+	`now|today|yesterday|tomorrow` +
+	`|(last|next) (second|minute|hour|day|week|month|year)s?` +
+	`|(a|an|one|two|three|four|five|six|seven|eight|nine|ten|[0-9]+) (second|minute|hour|day|week|month|year)s?( (ago|from now))?` +
+	`|in (a|an|one|two|three|four|five|six|seven|eight|nine|ten|[0-9]+) (second|minute|hour|day|week|month|year)s?` +
+	`|(last |next )?(sunday|monday|tuesday|wednesday|thursday|friday|saturday)` +
+	`|(last |next )?(january|february|march|april|may|june|july|august|september|october|november|december)` +
+	`|[0-9]{1,2}(:[0-9]{2}){0,2} ?(am|pm)` +
+	`)( at [0-9:apm ]+)?$`)
+
+// parseDateExpr resolves s, which may be an ISO date/time or a
+// natural-language expression like "2 weeks ago", "last friday" or
+// "yesterday", to an absolute time relative to now in the local zone.
+// Bare weekday names resolve to their most recent past occurrence, since
+// --since/--until are almost always used to look backwards.
+func parseDateExpr(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, fmt.Errorf("empty date expression")
+	}
+
+	layouts := []string{
+		"2006-01-02",
+		"2006-01-02 15:04:05",
+		"2006-01-02T15:04:05",
+		time.RFC3339,
+	}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	if !relativeDateExprPattern.MatchString(s) {
+		return time.Time{}, fmt.Errorf("couldn't parse date expression %q: not a recognized date, or relative expression like %q, %q, %q", s, "2 weeks ago", "last friday", "yesterday")
+	}
+
+	t, err := naturaldate.Parse(s, time.Now(), naturaldate.WithDirection(naturaldate.Past))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("couldn't parse date expression %q: %w", s, err)
+	}
+	return t, nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDateExprAccepts(t *testing.T) {
+	cases := []string{
+		"2006-01-02",
+		"now",
+		"today",
+		"yesterday",
+		"2 weeks ago",
+		"last friday",
+		"1 day",
+		"2 hours",
+		"3 months",
+		"5 minutes",
+		"1 week",
+		"next month",
+		"10am",
+	}
+	for _, s := range cases {
+		if _, err := parseDateExpr(s); err != nil {
+			t.Errorf("parseDateExpr(%q): unexpected error: %v", s, err)
+		}
+	}
+}
+
+func TestParseDateExprRejects(t *testing.T) {
+	cases := []string{
+		"",
+		"banana",
+		"xyz123notadate",
+	}
+	for _, s := range cases {
+		if _, err := parseDateExpr(s); err == nil {
+			t.Errorf("parseDateExpr(%q): expected error, got nil", s)
+		}
+	}
+}
+
+func TestParseDateExprBareQuantityMatchesAgo(t *testing.T) {
+	bare, err := parseDateExpr("2 hours")
+	if err != nil {
+		t.Fatalf("parseDateExpr(%q): unexpected error: %v", "2 hours", err)
+	}
+	if bare.After(time.Now()) {
+		t.Errorf("parseDateExpr(%q) = %v, want a time in the past", "2 hours", bare)
+	}
+}
@@ -1,10 +1,8 @@
 package main
 
 import (
-	"bufio"
 	"bytes"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -12,17 +10,43 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/EwanValentine/grecent/activity"
 )
 
 // Branch holds information about a git branch and its recency
 // based on the most recent commit on that branch or the latest
 // local work recorded in the branch reflog.
 type Branch struct {
-	Name        string    `json:"name"`
-	CommitHash  string    `json:"commitHash"`
-	CommitTime  time.Time `json:"commitTime"`
-	IsCurrent   bool      `json:"isCurrent"`
-	HasUpstream bool      `json:"hasUpstream"`
+	Name         string    `json:"name"`
+	CommitHash   string    `json:"commitHash"`
+	CommitTime   time.Time `json:"commitTime"`
+	IsCurrent    bool      `json:"isCurrent"`
+	HasUpstream  bool      `json:"hasUpstream"`
+	Upstream     string    `json:"upstream"` // e.g. "origin/feature", empty if none
+	UpstreamGone bool      `json:"upstreamGone"`
+
+	// Ahead/Behind/MergeBase/MergedInto are relative to the base branch
+	// (see --base) and are left zero-valued until annotateBaseStatus runs.
+	Ahead      int    `json:"ahead"`
+	Behind     int    `json:"behind"`
+	MergeBase  string `json:"mergeBase"`
+	MergedInto string `json:"mergedInto"`
+
+	// WorktreePath is set when the branch is checked out in a linked
+	// worktree other than the one grecent is running from.
+	WorktreePath string `json:"worktreePath,omitempty"`
+
+	// Raw activity signals (see the activity package) and the composite
+	// score computed from them, so downstream tools can re-rank with
+	// their own weighting instead of trusting ours.
+	CommitterDate     time.Time `json:"committerDate"`
+	AuthorDate        time.Time `json:"authorDate"`
+	ReflogTime        time.Time `json:"reflogTime"`
+	HasReflog         bool      `json:"hasReflog"`
+	UpstreamTipDate   time.Time `json:"upstreamTipDate"`
+	ReflogCountRecent int       `json:"reflogCountRecent"`
+	ActivityScore     float64   `json:"activityScore"`
 }
 
 func main() {
@@ -31,6 +55,13 @@ func main() {
 	doFetch := false
 	forceTUI := false
 	disableTUI := false
+	backend := ""
+	baseOverride := ""
+	dryRun := false
+	watch := false
+	sinceExpr := ""
+	untilExpr := ""
+	sortBy := "activity"
 
 	// Basic flag parsing without external deps
 	for i := 1; i < len(os.Args); i++ {
@@ -63,6 +94,64 @@ func main() {
 			doFetch = true
 			continue
 		}
+		if arg == "--dry-run" {
+			dryRun = true
+			continue
+		}
+		if arg == "--watch" {
+			watch = true
+			continue
+		}
+		if arg == "--sort" || strings.HasPrefix(arg, "--sort=") {
+			var val string
+			if strings.Contains(arg, "=") {
+				val = strings.SplitN(arg, "=", 2)[1]
+			} else {
+				if i+1 < len(os.Args) {
+					val = os.Args[i+1]
+					i++
+				} else {
+					fatal("--sort flag requires a value")
+				}
+			}
+			switch val {
+			case "activity", "committerdate", "reflog", "name":
+				sortBy = val
+			default:
+				fatal("unknown --sort %q (want activity, committerdate, reflog, or name)", val)
+			}
+			continue
+		}
+		if arg == "--since" || strings.HasPrefix(arg, "--since=") {
+			var val string
+			if strings.Contains(arg, "=") {
+				val = strings.SplitN(arg, "=", 2)[1]
+			} else {
+				if i+1 < len(os.Args) {
+					val = os.Args[i+1]
+					i++
+				} else {
+					fatal("--since flag requires a value")
+				}
+			}
+			sinceExpr = val
+			continue
+		}
+		if arg == "--until" || strings.HasPrefix(arg, "--until=") {
+			var val string
+			if strings.Contains(arg, "=") {
+				val = strings.SplitN(arg, "=", 2)[1]
+			} else {
+				if i+1 < len(os.Args) {
+					val = os.Args[i+1]
+					i++
+				} else {
+					fatal("--until flag requires a value")
+				}
+			}
+			untilExpr = val
+			continue
+		}
 		if arg == "--tui" {
 			forceTUI = true
 			continue
@@ -71,6 +160,36 @@ func main() {
 			disableTUI = true
 			continue
 		}
+		if arg == "--backend" || strings.HasPrefix(arg, "--backend=") {
+			var val string
+			if strings.Contains(arg, "=") {
+				val = strings.SplitN(arg, "=", 2)[1]
+			} else {
+				if i+1 < len(os.Args) {
+					val = os.Args[i+1]
+					i++
+				} else {
+					fatal("--backend flag requires a value")
+				}
+			}
+			backend = val
+			continue
+		}
+		if arg == "--base" || strings.HasPrefix(arg, "--base=") {
+			var val string
+			if strings.Contains(arg, "=") {
+				val = strings.SplitN(arg, "=", 2)[1]
+			} else {
+				if i+1 < len(os.Args) {
+					val = os.Args[i+1]
+					i++
+				} else {
+					fatal("--base flag requires a value")
+				}
+			}
+			baseOverride = val
+			continue
+		}
 		if arg == "-h" || arg == "--help" || arg == "help" {
 			usage()
 			return
@@ -81,19 +200,58 @@ func main() {
 		fatal("not a git repository (or any of the parent directories): .git")
 	}
 
+	repo, err := newRepo(backend)
+	if err != nil {
+		fatal("%v", err)
+	}
+
 	if doFetch {
-		_ = gitFetchAll()
+		_ = repo.FetchAll()
 	}
 
-	branches, err := getRecentBranches()
+	branches, err := repo.RecentBranches()
 	if err != nil {
 		fatal("%v", err)
 	}
 
+	if sinceExpr != "" || untilExpr != "" {
+		var since, until time.Time
+		if sinceExpr != "" {
+			since, err = parseDateExpr(sinceExpr)
+			if err != nil {
+				fatal("--since: %v", err)
+			}
+		}
+		if untilExpr != "" {
+			until, err = parseDateExpr(untilExpr)
+			if err != nil {
+				fatal("--until: %v", err)
+			}
+		}
+		branches = filterByTimeRange(branches, since, until)
+	}
+
+	weights, err := activity.LoadWeights()
+	if err != nil {
+		fatal("loading activity config: %v", err)
+	}
+	annotateActivity(branches, repo, weights)
+	sortBranches(branches, sortBy)
+
 	if len(branches) > limit {
 		branches = branches[:limit]
 	}
 
+	base := baseOverride
+	if base == "" {
+		if b, err := repo.DefaultBranch(); err == nil {
+			base = b
+		}
+	}
+	if base != "" {
+		annotateBaseStatus(branches, repo, base)
+	}
+
 	// Prefer JSON output if requested
 	if jsonOut {
 		enc := json.NewEncoder(os.Stdout)
@@ -103,8 +261,8 @@ func main() {
 	}
 
 	// Auto-run TUI if stdout is a terminal, unless disabled explicitly
-	if (forceTUI || (isTerminal(os.Stdout.Fd()) && !disableTUI)) && !jsonOut {
-		if err := runTUI(branches); err != nil {
+	if (forceTUI || (isTerminal() && !disableTUI)) && !jsonOut {
+		if err := runTUI(branches, repo, base, dryRun, watch, sortBy); err != nil {
 			fatal("%v", err)
 		}
 		return
@@ -120,195 +278,157 @@ func main() {
 		if len(hash) > 7 {
 			hash = hash[:7]
 		}
-		fmt.Printf("%s %-30s  %s  %s\n", current, b.Name, hash, humanizeTime(b.CommitTime))
-	}
-}
-
-func usage() {
-	fmt.Fprintf(os.Stderr, "grecent - list recent git branches by last activity\n\n")
-	fmt.Fprintf(os.Stderr, "Usage: grecent [-n N] [--json] [--fetch] [--tui|--no-tui]\n\n")
-	fmt.Fprintf(os.Stderr, "Options:\n")
-	fmt.Fprintf(os.Stderr, "  -n, --limit N   Limit number of branches (default 10)\n")
-	fmt.Fprintf(os.Stderr, "  --json          Output JSON\n")
-	fmt.Fprintf(os.Stderr, "  --fetch         Run 'git fetch --all --prune --tags' first to refresh remotes\n")
-	fmt.Fprintf(os.Stderr, "  --tui           Force TUI mode\n")
-	fmt.Fprintf(os.Stderr, "  --no-tui        Disable TUI even if stdout is a terminal\n")
-}
-
-func fatal(format string, args ...any) {
-	fmt.Fprintf(os.Stderr, format+"\n", args...)
-	os.Exit(1)
-}
-
-func isGitRepo() bool {
-	cmd := exec.Command("git", "rev-parse", "--is-inside-work-tree")
-	cmd.Stderr = new(bytes.Buffer)
-	out, err := cmd.Output()
-	if err != nil {
-		return false
+		wt := ""
+		if b.WorktreePath != "" {
+			wt = fmt.Sprintf("  wt: %s", b.WorktreePath)
+		}
+		fmt.Printf("%s %-30s  %s  %s  %s%s\n", current, b.Name, hash, humanizeTime(b.CommitTime), baseStatus(b), wt)
 	}
-	return strings.TrimSpace(string(out)) == "true"
-}
-
-func gitFetchAll() error {
-	cmd := exec.Command("git", "fetch", "--all", "--prune", "--tags", "--quiet")
-	cmd.Stdout = new(bytes.Buffer)
-	cmd.Stderr = new(bytes.Buffer)
-	return cmd.Run()
 }
 
-func getRecentBranches() ([]Branch, error) {
-	// Strategy:
-	// - Use for-each-ref to get local branches with their HEAD commit and committerdate
-	// - Identify current branch
-	// - For each branch, consider the latest reflog entry time for local work
-	// - Also consider upstream remote tip time (batch fetched) to approximate last push/fetch activity
-	// - Sort by the max(committerdate, reflogTime, remoteTipTime) desc
-	format := "%(refname:short)\t%(objectname)\t%(committerdate:iso-strict)\t%(upstream)\n"
-	cmd := exec.Command(
-		"git", "for-each-ref",
-		"--sort=-committerdate",
-		"--format", format,
-		"refs/heads",
-	)
-	out, err := cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("git for-each-ref failed: %w", err)
-	}
-
-	currentBranch, _ := getCurrentBranch()
-
-	remoteTimes := getRemoteBranchTimes()
-
-	scanner := bufio.NewScanner(bytes.NewReader(out))
-	branches := make([]Branch, 0, 32)
-	for scanner.Scan() {
-		line := scanner.Text()
-		fields := strings.Split(line, "\t")
-		if len(fields) < 4 {
+// annotateBaseStatus fills in Ahead, Behind, MergeBase and MergedInto on
+// each branch relative to base. Branches the merge-base/rev-list calls
+// fail for (e.g. base itself, or an unrelated history) are left zeroed.
+func annotateBaseStatus(branches []Branch, repo Repo, base string) {
+	for i := range branches {
+		b := &branches[i]
+		if b.Name == base {
 			continue
 		}
-		name := fields[0]
-		commit := fields[1]
-		commitTimeStr := fields[2]
-		upstreamRaw := fields[3]
-
-		commitTime, err := time.Parse(time.RFC3339, strings.TrimSpace(commitTimeStr))
+		mergeBase, err := repo.MergeBase(b.Name, base)
 		if err != nil {
-			commitTime, err = parseGitDate(commitTimeStr)
-			if err != nil {
-				return nil, fmt.Errorf("parse date for %s: %w", name, err)
-			}
+			continue
 		}
-
-		// Consider branch reflog latest entry time as "worked on" signal
-		if t, ok := getBranchReflogLatestTime(name); ok && t.After(commitTime) {
-			commitTime = t
+		ahead, behind, err := repo.AheadBehind(b.Name, base)
+		if err != nil {
+			continue
+		}
+		b.MergeBase = mergeBase
+		b.Ahead = ahead
+		b.Behind = behind
+		if ahead == 0 {
+			b.MergedInto = base
 		}
+	}
+}
 
-		// Consider remote tip time for upstream branch if available
-		upstreamShort := normalizeUpstream(upstreamRaw)
-		if upstreamShort != "" {
-			if rt, ok := remoteTimes[upstreamShort]; ok && rt.After(commitTime) {
-				commitTime = rt
+// annotateActivity fills in ReflogCountRecent and ActivityScore on each
+// branch from its already-populated raw signals plus a fresh count of
+// reflog entries inside weights.RecentReflogWindow.
+func annotateActivity(branches []Branch, repo Repo, weights activity.Weights) {
+	now := time.Now()
+	cutoff := now.Add(-weights.RecentReflogWindow)
+	for i := range branches {
+		b := &branches[i]
+		recent := 0
+		if times, err := repo.ReflogTimes(b.Name); err == nil {
+			for _, t := range times {
+				if t.After(cutoff) {
+					recent++
+				}
 			}
 		}
+		b.ReflogCountRecent = recent
+		b.ActivityScore = activity.Score(activity.Signals{
+			CommitterDate:     b.CommitterDate,
+			AuthorDate:        b.AuthorDate,
+			ReflogTime:        b.ReflogTime,
+			HasReflog:         b.HasReflog,
+			UpstreamTipDate:   b.UpstreamTipDate,
+			HasUpstreamTip:    b.HasUpstream && !b.UpstreamTipDate.IsZero(),
+			ReflogCountRecent: recent,
+			IsHead:            b.IsCurrent,
+		}, weights, now)
+	}
+}
 
-		branches = append(branches, Branch{
-			Name:        name,
-			CommitHash:  commit,
-			CommitTime:  commitTime,
-			IsCurrent:   name == currentBranch,
-			HasUpstream: upstreamShort != "",
+// sortBranches reorders branches in place according to mode, one of
+// "activity" (the composite score, default), "committerdate", "reflog",
+// or "name".
+func sortBranches(branches []Branch, mode string) {
+	switch mode {
+	case "name":
+		sort.SliceStable(branches, func(i, j int) bool {
+			return branches[i].Name < branches[j].Name
+		})
+	case "committerdate":
+		sort.SliceStable(branches, func(i, j int) bool {
+			return branches[i].CommitterDate.After(branches[j].CommitterDate)
+		})
+	case "reflog":
+		sort.SliceStable(branches, func(i, j int) bool {
+			return branches[i].ReflogTime.After(branches[j].ReflogTime)
+		})
+	default: // "activity"
+		sort.SliceStable(branches, func(i, j int) bool {
+			return branches[i].ActivityScore > branches[j].ActivityScore
 		})
 	}
-	if err := scanner.Err(); err != nil {
-		return nil, err
-	}
-
-	// Sort by computed activity time desc
-	sort.SliceStable(branches, func(i, j int) bool {
-		return branches[i].CommitTime.After(branches[j].CommitTime)
-	})
-
-	return branches, nil
 }
 
-func getCurrentBranch() (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
-	out, err := cmd.Output()
-	if err != nil {
-		return "", err
-	}
-	branch := strings.TrimSpace(string(out))
-	if branch == "HEAD" {
-		return "", errors.New("detached HEAD")
+// filterByTimeRange keeps only branches whose CommitTime falls within
+// [since, until]. A zero since or until leaves that side unbounded.
+func filterByTimeRange(branches []Branch, since, until time.Time) []Branch {
+	out := branches[:0]
+	for _, b := range branches {
+		if !since.IsZero() && b.CommitTime.Before(since) {
+			continue
+		}
+		if !until.IsZero() && b.CommitTime.After(until) {
+			continue
+		}
+		out = append(out, b)
 	}
-	return branch, nil
+	return out
 }
 
-func getBranchReflogLatestTime(branch string) (time.Time, bool) {
-	// Ask for latest reflog entry on the branch ref
-	// The %gd token includes the date in the reflog selector when --date=iso-strict
-	cmd := exec.Command(
-		"git", "reflog",
-		"--date=iso-strict",
-		"--pretty=%gd",
-		"-n", "1",
-		"refs/heads/"+branch,
-	)
-	out, err := cmd.Output()
-	if err != nil || len(out) == 0 {
-		return time.Time{}, false
+// baseStatus renders the ahead/behind/merged/gone summary shown in plain
+// and TUI output, e.g. "↑2 ↓5", "merged", or "gone".
+func baseStatus(b Branch) string {
+	if b.UpstreamGone {
+		return "gone"
 	}
-	line := strings.TrimSpace(string(out))
-	// Example: refs/heads/feature@{2025-08-07T11:35:23+02:00}
-	start := strings.Index(line, "@{")
-	end := strings.LastIndex(line, "}")
-	if start == -1 || end == -1 || start+2 >= end {
-		return time.Time{}, false
+	if b.MergedInto != "" {
+		return fmt.Sprintf("merged into %s", b.MergedInto)
 	}
-	dateStr := line[start+2 : end]
-	if t, err := time.Parse(time.RFC3339, dateStr); err == nil {
-		return t, true
-	}
-	// lenient fallback
-	if t, err := parseGitDate(dateStr); err == nil {
-		return t, true
+	if b.Ahead == 0 && b.Behind == 0 {
+		return ""
 	}
-	return time.Time{}, false
+	return fmt.Sprintf("↑%d ↓%d", b.Ahead, b.Behind)
 }
 
-func getRemoteBranchTimes() map[string]time.Time {
-	// Batch query remote branches tip committer dates
-	cmd := exec.Command(
-		"git", "for-each-ref",
-		"--format", "%(refname:short)\t%(committerdate:iso-strict)",
-		"refs/remotes",
-	)
+func usage() {
+	fmt.Fprintf(os.Stderr, "grecent - list recent git branches by last activity\n\n")
+	fmt.Fprintf(os.Stderr, "Usage: grecent [-n N] [--json] [--fetch] [--tui|--no-tui] [--backend=go-git|shell] [--base=BRANCH] [--dry-run] [--watch] [--since=EXPR] [--until=EXPR] [--sort=activity|committerdate|reflog|name]\n\n")
+	fmt.Fprintf(os.Stderr, "Options:\n")
+	fmt.Fprintf(os.Stderr, "  -n, --limit N   Limit number of branches (default 10)\n")
+	fmt.Fprintf(os.Stderr, "  --json          Output JSON\n")
+	fmt.Fprintf(os.Stderr, "  --fetch         Run 'git fetch --all --prune --tags' first to refresh remotes\n")
+	fmt.Fprintf(os.Stderr, "  --tui           Force TUI mode\n")
+	fmt.Fprintf(os.Stderr, "  --no-tui        Disable TUI even if stdout is a terminal\n")
+	fmt.Fprintf(os.Stderr, "  --backend NAME  Git backend to use: go-git (default, falls back to shell) or shell\n")
+	fmt.Fprintf(os.Stderr, "  --base BRANCH   Base branch to compare against for ahead/behind/merged status\n")
+	fmt.Fprintf(os.Stderr, "                  (default: origin/HEAD, falling back to main or master)\n")
+	fmt.Fprintf(os.Stderr, "  --dry-run       Start the TUI with cleanup actions in dry-run mode (toggle with D)\n")
+	fmt.Fprintf(os.Stderr, "  --watch         Start the TUI watching .git for ref/reflog changes and auto-refresh (toggle with t)\n")
+	fmt.Fprintf(os.Stderr, "  --since EXPR    Only show branches active at or after EXPR (ISO date or natural language, e.g. '2 weeks ago')\n")
+	fmt.Fprintf(os.Stderr, "  --until EXPR    Only show branches active at or before EXPR\n")
+	fmt.Fprintf(os.Stderr, "  --sort MODE     Order branches by activity (default, see ~/.config/grecent/config.toml), committerdate, reflog, or name\n")
+}
+
+func fatal(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
+
+func isGitRepo() bool {
+	cmd := exec.Command("git", "rev-parse", "--is-inside-work-tree")
+	cmd.Stderr = new(bytes.Buffer)
 	out, err := cmd.Output()
-	if err != nil || len(out) == 0 {
-		return map[string]time.Time{}
-	}
-	result := make(map[string]time.Time, 64)
-	scanner := bufio.NewScanner(bytes.NewReader(out))
-	for scanner.Scan() {
-		line := scanner.Text()
-		parts := strings.SplitN(line, "\t", 2)
-		if len(parts) != 2 {
-			continue
-		}
-		name := strings.TrimSpace(parts[0]) // e.g., origin/feature
-		dateStr := strings.TrimSpace(parts[1])
-		if t, err := time.Parse(time.RFC3339, dateStr); err == nil {
-			result[name] = t
-			continue
-		}
-		if t, err := parseGitDate(dateStr); err == nil {
-			result[name] = t
-		}
+	if err != nil {
+		return false
 	}
-	return result
+	return strings.TrimSpace(string(out)) == "true"
 }
 
 func normalizeUpstream(us string) string {
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Repo abstracts the git operations grecent needs: reading branch state
+// (for listing and sorting) and the handful of mutating commands the TUI
+// can trigger. It exists so the default shell-out implementation can sit
+// alongside an in-process go-git backend without the rest of the program
+// caring which one is in play.
+type Repo interface {
+	// CurrentBranch returns the name of the checked-out branch.
+	CurrentBranch() (string, error)
+	// RecentBranches returns local branches annotated with their best-guess
+	// last-activity time, sorted most-recent first.
+	RecentBranches() ([]Branch, error)
+	// ReflogLatestTime returns the timestamp of the most recent reflog
+	// entry for branch, if any.
+	ReflogLatestTime(branch string) (time.Time, bool)
+	// ReflogTimes returns the timestamp of every reflog entry for branch,
+	// most recent first. A branch with no reflog returns (nil, nil).
+	ReflogTimes(branch string) ([]time.Time, error)
+	// RemoteBranchTimes returns committer dates for every remote-tracking
+	// branch, keyed by short name (e.g. "origin/main").
+	RemoteBranchTimes() map[string]time.Time
+	// CheckoutBranch switches the working tree to branch.
+	CheckoutBranch(name string) error
+	// DeleteBranch removes a local branch, force-deleting if needed.
+	DeleteBranch(name string) error
+	// DeleteRemoteBranch push-deletes branch from remote.
+	DeleteRemoteBranch(remote, branch string) error
+	// MergeIntoCurrent merges branch into the currently checked-out branch.
+	MergeIntoCurrent(name string) error
+	// FetchAll fetches and prunes all remotes.
+	FetchAll() error
+	// DefaultBranch returns the repo's base branch: origin's HEAD symlink
+	// if one is set, otherwise "main" or "master", whichever exists.
+	DefaultBranch() (string, error)
+	// MergeBase returns the best common ancestor of branch and base.
+	MergeBase(branch, base string) (string, error)
+	// AheadBehind returns how many commits branch has that base doesn't
+	// (ahead) and vice versa (behind), relative to their merge-base.
+	AheadBehind(branch, base string) (ahead int, behind int, err error)
+	// WorktreePaths returns, for each local branch checked out in a linked
+	// worktree other than the one grecent is running from, the absolute
+	// path of that worktree.
+	WorktreePaths() (map[string]string, error)
+	// AddWorktree creates a new worktree for branch under dir (which is
+	// created if needed) and returns its path.
+	AddWorktree(branch, dir string) (string, error)
+	// GitDir returns the repository's .git directory, for watch mode to
+	// fsnotify-watch refs and reflogs directly.
+	GitDir() (string, error)
+}
+
+// ErrBranchCheckedOut is returned by CheckoutBranch when the branch is
+// already checked out in another worktree; plain `git checkout` would
+// fail with a similar error, so callers should offer AddWorktree/the
+// worktree path instead.
+type ErrBranchCheckedOut struct {
+	Branch string
+	Path   string
+}
+
+func (e *ErrBranchCheckedOut) Error() string {
+	return fmt.Sprintf("branch %s is already checked out at %s", e.Branch, e.Path)
+}
+
+const (
+	backendShell = "shell"
+	backendGoGit = "go-git"
+)
+
+// newRepo resolves backend ("", "shell", or "go-git") to a Repo
+// implementation. An empty backend auto-selects go-git when it can open
+// the repository in the current directory, falling back to the shell
+// backend for repo formats or features go-git doesn't support.
+func newRepo(backend string) (Repo, error) {
+	switch backend {
+	case backendShell:
+		return newShellRepo(), nil
+	case backendGoGit:
+		return newGoGitRepo(".")
+	case "":
+		if r, err := newGoGitRepo("."); err == nil {
+			return r, nil
+		}
+		return newShellRepo(), nil
+	default:
+		return nil, fmt.Errorf("unknown --backend %q (want %q or %q)", backend, backendShell, backendGoGit)
+	}
+}
@@ -0,0 +1,334 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// gogitRepo implements Repo by reading the object database directly via
+// go-git. Listing branches, reflogs and upstreams this way avoids spawning
+// a git process per branch, which is where shellRepo spends most of its
+// time on repos with hundreds of branches.
+//
+// go-git doesn't expose a reflog reader, so reflog times are read straight
+// off the on-disk reflog files whose layout go-git already assumes
+// elsewhere. Mutating operations (checkout/delete/merge/fetch) are
+// delegated to an embedded shellRepo: go-git's merge and checkout support
+// doesn't cover everything the plain git CLI does, and there's no speed
+// to gain on a one-shot mutation the way there is reading every branch.
+type gogitRepo struct {
+	repo   *git.Repository
+	gitDir string
+	shell  *shellRepo
+}
+
+func newGoGitRepo(path string) (*gogitRepo, error) {
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return nil, fmt.Errorf("go-git: open %s: %w", path, err)
+	}
+	gitDir := filepath.Join(path, ".git")
+	if wt, err := repo.Worktree(); err == nil && wt != nil {
+		gitDir = filepath.Join(wt.Filesystem.Root(), ".git")
+	}
+	return &gogitRepo{repo: repo, gitDir: gitDir, shell: newShellRepo()}, nil
+}
+
+func (r *gogitRepo) CurrentBranch() (string, error) {
+	head, err := r.repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("go-git: resolve HEAD: %w", err)
+	}
+	if !head.Name().IsBranch() {
+		return "", fmt.Errorf("detached HEAD")
+	}
+	return head.Name().Short(), nil
+}
+
+func (r *gogitRepo) RecentBranches() ([]Branch, error) {
+	currentBranch, _ := r.CurrentBranch()
+	remoteTimes := r.RemoteBranchTimes()
+	worktrees, _ := r.WorktreePaths()
+
+	cfg, err := r.repo.Config()
+	if err != nil {
+		return nil, fmt.Errorf("go-git: read config: %w", err)
+	}
+
+	refs, err := r.repo.Branches()
+	if err != nil {
+		return nil, fmt.Errorf("go-git: list branches: %w", err)
+	}
+
+	branches := make([]Branch, 0, 32)
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name().Short()
+
+		commit, err := r.repo.CommitObject(ref.Hash())
+		if err != nil {
+			return fmt.Errorf("resolve tip of %s: %w", name, err)
+		}
+		committerDate := commit.Committer.When
+		authorDate := commit.Author.When
+		commitTime := committerDate
+
+		reflogTime, hasReflog := r.ReflogLatestTime(name)
+		if hasReflog && reflogTime.After(commitTime) {
+			commitTime = reflogTime
+		}
+
+		upstreamShort := ""
+		upstreamGone := false
+		var upstreamTipDate time.Time
+		if bcfg, ok := cfg.Branches[name]; ok && bcfg.Remote != "" && bcfg.Merge != "" {
+			upstreamShort = bcfg.Remote + "/" + bcfg.Merge.Short()
+			if rt, ok := remoteTimes[upstreamShort]; ok {
+				upstreamTipDate = rt
+				if rt.After(commitTime) {
+					commitTime = rt
+				}
+			} else {
+				upstreamGone = true
+			}
+		}
+
+		branches = append(branches, Branch{
+			Name:            name,
+			CommitHash:      ref.Hash().String(),
+			CommitTime:      commitTime,
+			IsCurrent:       name == currentBranch,
+			HasUpstream:     upstreamShort != "",
+			Upstream:        upstreamShort,
+			UpstreamGone:    upstreamGone,
+			WorktreePath:    worktrees[name],
+			CommitterDate:   committerDate,
+			AuthorDate:      authorDate,
+			ReflogTime:      reflogTime,
+			HasReflog:       hasReflog,
+			UpstreamTipDate: upstreamTipDate,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(branches, func(i, j int) bool {
+		return branches[i].CommitTime.After(branches[j].CommitTime)
+	})
+	return branches, nil
+}
+
+func (r *gogitRepo) ReflogLatestTime(branch string) (time.Time, bool) {
+	times, err := r.ReflogTimes(branch)
+	if err != nil || len(times) == 0 {
+		return time.Time{}, false
+	}
+	return times[0], true
+}
+
+// ReflogTimes reads .git/logs/refs/heads/<branch> directly, since go-git
+// has no reflog-reading API of its own, and returns its entries most
+// recent first. Each line has the form
+// "<old-sha> <new-sha> <name> <email> <unix-ts> <tz>\t<msg>".
+func (r *gogitRepo) ReflogTimes(branch string) ([]time.Time, error) {
+	path := filepath.Join(r.gitDir, "logs", "refs", "heads", filepath.FromSlash(branch))
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil
+	}
+	defer f.Close()
+
+	var times []time.Time
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		header := strings.SplitN(line, "\t", 2)[0]
+		fields := strings.Fields(header)
+		if len(fields) < 2 {
+			continue
+		}
+		ts, err := strconv.ParseInt(fields[len(fields)-2], 10, 64)
+		if err != nil {
+			continue
+		}
+		t := time.Unix(ts, 0)
+		if loc, err := parseTZOffset(fields[len(fields)-1]); err == nil {
+			t = t.In(loc)
+		}
+		times = append(times, t)
+	}
+	// The log file is oldest-first; reverse to match shellRepo's
+	// most-recent-first order.
+	for i, j := 0, len(times)-1; i < j; i, j = i+1, j-1 {
+		times[i], times[j] = times[j], times[i]
+	}
+	return times, scanner.Err()
+}
+
+func (r *gogitRepo) RemoteBranchTimes() map[string]time.Time {
+	result := make(map[string]time.Time, 64)
+	refs, err := r.repo.References()
+	if err != nil {
+		return result
+	}
+	_ = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Name().IsRemote() {
+			if commit, err := r.repo.CommitObject(ref.Hash()); err == nil {
+				result[ref.Name().Short()] = commit.Committer.When
+			}
+		}
+		return nil
+	})
+	return result
+}
+
+func (r *gogitRepo) DefaultBranch() (string, error) {
+	if ref, err := r.repo.Reference(plumbing.ReferenceName("refs/remotes/origin/HEAD"), false); err == nil {
+		if ref.Type() == plumbing.SymbolicReference {
+			return strings.TrimPrefix(ref.Target().Short(), "origin/"), nil
+		}
+	}
+	for _, name := range []string{"main", "master"} {
+		if _, err := r.repo.Reference(plumbing.NewBranchReferenceName(name), true); err == nil {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("could not determine default branch: no origin/HEAD, main, or master")
+}
+
+func (r *gogitRepo) MergeBase(branch, base string) (string, error) {
+	branchCommit, err := r.commitForBranch(branch)
+	if err != nil {
+		return "", err
+	}
+	baseCommit, err := r.commitForBranch(base)
+	if err != nil {
+		return "", err
+	}
+	bases, err := branchCommit.MergeBase(baseCommit)
+	if err != nil {
+		return "", fmt.Errorf("go-git: merge-base %s %s: %w", base, branch, err)
+	}
+	if len(bases) == 0 {
+		return "", fmt.Errorf("no common ancestor between %s and %s", base, branch)
+	}
+	return bases[0].Hash.String(), nil
+}
+
+// AheadBehind counts commits unique to each side of the branch/base
+// merge-base by collecting one side's full ancestor set and then walking
+// the other side's history with that set pruning the walk, matching
+// `git rev-list --left-right --count`.
+func (r *gogitRepo) AheadBehind(branch, base string) (int, int, error) {
+	branchCommit, err := r.commitForBranch(branch)
+	if err != nil {
+		return 0, 0, err
+	}
+	baseCommit, err := r.commitForBranch(base)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	baseAncestors, err := ancestorSet(baseCommit)
+	if err != nil {
+		return 0, 0, err
+	}
+	ahead, err := countUntilKnown(branchCommit, baseAncestors)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	branchAncestors, err := ancestorSet(branchCommit)
+	if err != nil {
+		return 0, 0, err
+	}
+	behind, err := countUntilKnown(baseCommit, branchAncestors)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return ahead, behind, nil
+}
+
+func (r *gogitRepo) commitForBranch(name string) (*object.Commit, error) {
+	ref, err := r.repo.Reference(plumbing.NewBranchReferenceName(name), true)
+	if err != nil {
+		return nil, fmt.Errorf("resolve branch %s: %w", name, err)
+	}
+	return r.repo.CommitObject(ref.Hash())
+}
+
+func ancestorSet(from *object.Commit) (map[plumbing.Hash]bool, error) {
+	set := make(map[plumbing.Hash]bool, 256)
+	iter := object.NewCommitIterCTime(from, nil, nil)
+	err := iter.ForEach(func(c *object.Commit) error {
+		set[c.Hash] = true
+		return nil
+	})
+	return set, err
+}
+
+// countUntilKnown walks from's history and counts every commit reachable
+// from it that isn't in known. known is passed as the walk's seenExternal
+// set rather than checked per popped commit: the walk is a heap merge of
+// every ancestry path, so stopping on the first known commit popped (via
+// storer.ErrStop) would abandon whatever else was still queued from other
+// parents, undercounting histories with merge commits. Seeding
+// seenExternal instead prunes each known commit's subtree as soon as it's
+// reached, without aborting the rest of the heap.
+func countUntilKnown(from *object.Commit, known map[plumbing.Hash]bool) (int, error) {
+	count := 0
+	iter := object.NewCommitIterCTime(from, known, nil)
+	err := iter.ForEach(func(c *object.Commit) error {
+		count++
+		return nil
+	})
+	return count, err
+}
+
+func (r *gogitRepo) CheckoutBranch(name string) error { return r.shell.CheckoutBranch(name) }
+func (r *gogitRepo) DeleteBranch(name string) error   { return r.shell.DeleteBranch(name) }
+func (r *gogitRepo) DeleteRemoteBranch(remote, branch string) error {
+	return r.shell.DeleteRemoteBranch(remote, branch)
+}
+func (r *gogitRepo) MergeIntoCurrent(name string) error        { return r.shell.MergeIntoCurrent(name) }
+func (r *gogitRepo) FetchAll() error                           { return r.shell.FetchAll() }
+func (r *gogitRepo) WorktreePaths() (map[string]string, error) { return r.shell.WorktreePaths() }
+func (r *gogitRepo) AddWorktree(branch, dir string) (string, error) {
+	return r.shell.AddWorktree(branch, dir)
+}
+func (r *gogitRepo) GitDir() (string, error) { return r.gitDir, nil }
+
+// parseTZOffset parses a git reflog timezone field like "+0200" or "-0700".
+func parseTZOffset(s string) (*time.Location, error) {
+	if len(s) != 5 || (s[0] != '+' && s[0] != '-') {
+		return nil, fmt.Errorf("invalid timezone offset %q", s)
+	}
+	hours, err := strconv.Atoi(s[1:3])
+	if err != nil {
+		return nil, err
+	}
+	mins, err := strconv.Atoi(s[3:5])
+	if err != nil {
+		return nil, err
+	}
+	offset := hours*3600 + mins*60
+	if s[0] == '-' {
+		offset = -offset
+	}
+	return time.FixedZone(s, offset), nil
+}
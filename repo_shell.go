@@ -0,0 +1,365 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// shellRepo implements Repo by shelling out to the git binary, exactly as
+// grecent has always worked. It's the fallback backend for repos (or git
+// features) the go-git backend can't handle.
+type shellRepo struct{}
+
+func newShellRepo() *shellRepo { return &shellRepo{} }
+
+func (r *shellRepo) CurrentBranch() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	branch := strings.TrimSpace(string(out))
+	if branch == "HEAD" {
+		return "", errors.New("detached HEAD")
+	}
+	return branch, nil
+}
+
+func (r *shellRepo) RecentBranches() ([]Branch, error) {
+	// Strategy:
+	// - Use for-each-ref to get local branches with their HEAD commit and committerdate
+	// - Identify current branch
+	// - For each branch, consider the latest reflog entry time for local work
+	// - Also consider upstream remote tip time (batch fetched) to approximate last push/fetch activity
+	// - Sort by the max(committerdate, reflogTime, remoteTipTime) desc
+	format := "%(refname:short)\t%(objectname)\t%(committerdate:iso-strict)\t%(authordate:iso-strict)\t%(upstream)\n"
+	cmd := exec.Command(
+		"git", "for-each-ref",
+		"--sort=-committerdate",
+		"--format", format,
+		"refs/heads",
+	)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git for-each-ref failed: %w", err)
+	}
+
+	currentBranch, _ := r.CurrentBranch()
+
+	remoteTimes := r.RemoteBranchTimes()
+	worktrees, _ := r.WorktreePaths()
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	branches := make([]Branch, 0, 32)
+	for scanner.Scan() {
+		line := scanner.Text()
+		fields := strings.Split(line, "\t")
+		if len(fields) < 5 {
+			continue
+		}
+		name := fields[0]
+		commit := fields[1]
+		commitTimeStr := fields[2]
+		authorTimeStr := fields[3]
+		upstreamRaw := fields[4]
+
+		commitTime, err := time.Parse(time.RFC3339, strings.TrimSpace(commitTimeStr))
+		if err != nil {
+			commitTime, err = parseGitDate(commitTimeStr)
+			if err != nil {
+				return nil, fmt.Errorf("parse date for %s: %w", name, err)
+			}
+		}
+		committerDate := commitTime
+		authorDate, err := time.Parse(time.RFC3339, strings.TrimSpace(authorTimeStr))
+		if err != nil {
+			authorDate, _ = parseGitDate(authorTimeStr)
+		}
+
+		// Consider branch reflog latest entry time as "worked on" signal
+		reflogTime, hasReflog := r.ReflogLatestTime(name)
+		if hasReflog && reflogTime.After(commitTime) {
+			commitTime = reflogTime
+		}
+
+		// Consider remote tip time for upstream branch if available
+		upstreamShort := normalizeUpstream(upstreamRaw)
+		upstreamGone := false
+		upstreamTipDate := remoteTimes[upstreamShort]
+		if upstreamShort != "" {
+			if rt, ok := remoteTimes[upstreamShort]; ok {
+				if rt.After(commitTime) {
+					commitTime = rt
+				}
+			} else {
+				// Configured upstream with no matching remote-tracking ref:
+				// it was deleted on the remote (and pruned locally).
+				upstreamGone = true
+			}
+		}
+
+		branches = append(branches, Branch{
+			Name:            name,
+			CommitHash:      commit,
+			CommitTime:      commitTime,
+			IsCurrent:       name == currentBranch,
+			HasUpstream:     upstreamShort != "",
+			Upstream:        upstreamShort,
+			UpstreamGone:    upstreamGone,
+			WorktreePath:    worktrees[name],
+			CommitterDate:   committerDate,
+			AuthorDate:      authorDate,
+			ReflogTime:      reflogTime,
+			HasReflog:       hasReflog,
+			UpstreamTipDate: upstreamTipDate,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// Sort by computed activity time desc
+	sort.SliceStable(branches, func(i, j int) bool {
+		return branches[i].CommitTime.After(branches[j].CommitTime)
+	})
+
+	return branches, nil
+}
+
+func (r *shellRepo) ReflogLatestTime(branch string) (time.Time, bool) {
+	times, err := r.ReflogTimes(branch)
+	if err != nil || len(times) == 0 {
+		return time.Time{}, false
+	}
+	return times[0], true
+}
+
+// ReflogTimes returns every reflog entry's date, most recent first
+// (git reflog's own default order). The %gd token includes the date in
+// the reflog selector when --date=iso-strict.
+func (r *shellRepo) ReflogTimes(branch string) ([]time.Time, error) {
+	cmd := exec.Command(
+		"git", "reflog",
+		"--date=iso-strict",
+		"--pretty=%gd",
+		"refs/heads/"+branch,
+	)
+	out, err := cmd.Output()
+	if err != nil || len(out) == 0 {
+		return nil, nil
+	}
+	var times []time.Time
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		// Example: refs/heads/feature@{2025-08-07T11:35:23+02:00}
+		start := strings.Index(line, "@{")
+		end := strings.LastIndex(line, "}")
+		if start == -1 || end == -1 || start+2 >= end {
+			continue
+		}
+		dateStr := line[start+2 : end]
+		if t, err := time.Parse(time.RFC3339, dateStr); err == nil {
+			times = append(times, t)
+			continue
+		}
+		if t, err := parseGitDate(dateStr); err == nil {
+			times = append(times, t)
+		}
+	}
+	return times, scanner.Err()
+}
+
+func (r *shellRepo) RemoteBranchTimes() map[string]time.Time {
+	// Batch query remote branches tip committer dates
+	cmd := exec.Command(
+		"git", "for-each-ref",
+		"--format", "%(refname:short)\t%(committerdate:iso-strict)",
+		"refs/remotes",
+	)
+	out, err := cmd.Output()
+	if err != nil || len(out) == 0 {
+		return map[string]time.Time{}
+	}
+	result := make(map[string]time.Time, 64)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		parts := strings.SplitN(line, "\t", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		name := strings.TrimSpace(parts[0]) // e.g., origin/feature
+		dateStr := strings.TrimSpace(parts[1])
+		if t, err := time.Parse(time.RFC3339, dateStr); err == nil {
+			result[name] = t
+			continue
+		}
+		if t, err := parseGitDate(dateStr); err == nil {
+			result[name] = t
+		}
+	}
+	return result
+}
+
+func (r *shellRepo) CheckoutBranch(name string) error {
+	worktrees, err := r.WorktreePaths()
+	if err == nil {
+		if path, ok := worktrees[name]; ok {
+			return &ErrBranchCheckedOut{Branch: name, Path: path}
+		}
+	}
+	return runCmdSilent("git", "checkout", name)
+}
+
+// WorktreePaths parses `git worktree list --porcelain` into a map of
+// branch name to worktree path, skipping the worktree grecent is running
+// from (its branch is reached via plain checkout, not another worktree).
+func (r *shellRepo) WorktreePaths() (map[string]string, error) {
+	toplevel, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git rev-parse --show-toplevel: %w", err)
+	}
+	current := strings.TrimSpace(string(toplevel))
+
+	cmd := exec.Command("git", "worktree", "list", "--porcelain")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git worktree list: %w", err)
+	}
+
+	result := make(map[string]string)
+	var path string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "worktree "):
+			path = strings.TrimPrefix(line, "worktree ")
+		case strings.HasPrefix(line, "branch "):
+			branch := strings.TrimPrefix(strings.TrimPrefix(line, "branch "), "refs/heads/")
+			if path != current {
+				result[branch] = path
+			}
+		case line == "":
+			path = ""
+		}
+	}
+	return result, scanner.Err()
+}
+
+// AddWorktree runs `git worktree add` for branch under dir, creating dir
+// if it doesn't exist, and returns the new worktree's path.
+func (r *shellRepo) AddWorktree(branch, dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create worktree dir %s: %w", dir, err)
+	}
+	path := filepath.Join(dir, branch)
+	if err := runCmdSilent("git", "worktree", "add", path, branch); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func (r *shellRepo) DeleteBranch(name string) error {
+	// Try safe delete; if it fails, attempt force delete
+	if err := runCmdSilent("git", "branch", "-d", name); err != nil {
+		return runCmdSilent("git", "branch", "-D", name)
+	}
+	return nil
+}
+
+func (r *shellRepo) DeleteRemoteBranch(remote, branch string) error {
+	return runCmdSilent("git", "push", remote, "--delete", branch)
+}
+
+func (r *shellRepo) MergeIntoCurrent(name string) error {
+	// Merge selected branch into current HEAD
+	return runCmdSilent("git", "merge", name)
+}
+
+func (r *shellRepo) FetchAll() error {
+	cmd := exec.Command("git", "fetch", "--all", "--prune", "--tags", "--quiet")
+	cmd.Stdout = new(bytes.Buffer)
+	cmd.Stderr = new(bytes.Buffer)
+	return cmd.Run()
+}
+
+func (r *shellRepo) GitDir() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--git-dir")
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse --git-dir: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (r *shellRepo) DefaultBranch() (string, error) {
+	cmd := exec.Command("git", "symbolic-ref", "--short", "refs/remotes/origin/HEAD")
+	if out, err := cmd.Output(); err == nil {
+		return normalizeUpstream(strings.TrimSpace(string(out))), nil
+	}
+	for _, name := range []string{"main", "master"} {
+		if exec.Command("git", "show-ref", "--verify", "--quiet", "refs/heads/"+name).Run() == nil {
+			return name, nil
+		}
+	}
+	return "", errors.New("could not determine default branch: no origin/HEAD, main, or master")
+}
+
+func (r *shellRepo) MergeBase(branch, base string) (string, error) {
+	cmd := exec.Command("git", "merge-base", base, branch)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git merge-base %s %s: %w", base, branch, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (r *shellRepo) AheadBehind(branch, base string) (int, int, error) {
+	cmd := exec.Command("git", "rev-list", "--left-right", "--count", base+"..."+branch)
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, 0, fmt.Errorf("git rev-list %s...%s: %w", base, branch, err)
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rev-list output: %q", out)
+	}
+	behind, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	ahead, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	return ahead, behind, nil
+}
+
+func runCmdSilent(name string, args ...string) error {
+	cmd := newCommand(name, args...)
+	return cmd.Run()
+}
+
+// newCommand wraps exec.Command for testing/mocking if needed
+func newCommand(name string, args ...string) *exec.Cmd {
+	return execCommand(name, args...)
+}
+
+var execCommand = func(name string, args ...string) *exec.Cmd {
+	return osCommand(name, args...)
+}
+
+var osCommand = func(name string, args ...string) *exec.Cmd {
+	return exec.Command(name, args...)
+}
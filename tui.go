@@ -1,15 +1,17 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
-	"sort"
+	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/EwanValentine/grecent/activity"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/fsnotify/fsnotify"
 	"github.com/lithammer/fuzzysearch/fuzzy"
 )
 
@@ -27,6 +29,8 @@ func isTerminal() bool {
 // TUI model
 
 type model struct {
+	repo     Repo
+	base     string
 	branches []Branch
 	filtered []Branch
 	cursor   int
@@ -34,43 +38,101 @@ type model struct {
 	height   int
 	search   string
 	status   string
-	sortBy   string // name|time
-	sortDesc bool
+	sortBy   string // one of sortModes: activity|committerdate|reflog|name
 
 	confirming bool
 	action     string // "delete" | "merge"
+
+	selected    map[string]bool
+	rangeAnchor int // index into m.filtered where a "V" range started, -1 if none
+	dryRun      bool
+
+	cleanupMenu        bool
+	pendingBulkTargets []Branch
+
+	watching bool
+	watcher  *fsnotify.Watcher
+
+	weights activity.Weights
 }
 
-func initialModel(branches []Branch) model {
+// sortModes are the orderings "s" cycles through in the TUI, matching
+// the values accepted by --sort.
+var sortModes = []string{"activity", "committerdate", "reflog", "name"}
+
+func initialModel(branches []Branch, repo Repo, base string, dryRun, watch bool, sortBy string) model {
+	weights, err := activity.LoadWeights()
+	if err != nil {
+		weights = activity.DefaultWeights()
+	}
 	m := model{
-		branches: branches,
-		filtered: make([]Branch, len(branches)),
-		cursor:   0,
-		search:   "",
-		sortBy:   "time",
-		sortDesc: true,
+		repo:        repo,
+		base:        base,
+		branches:    branches,
+		filtered:    make([]Branch, len(branches)),
+		cursor:      0,
+		search:      "",
+		sortBy:      sortBy,
+		selected:    make(map[string]bool),
+		rangeAnchor: -1,
+		dryRun:      dryRun,
+		weights:     weights,
 	}
 	copy(m.filtered, branches)
 	m.applySortFilter()
+	if watch {
+		if gitDir, err := repo.GitDir(); err == nil {
+			if w, err := newGitWatcher(gitDir); err == nil {
+				m.watcher = w
+				m.watching = true
+			}
+		}
+	}
 	return m
 }
 
-func runTUI(branches []Branch) error {
-	p := tea.NewProgram(initialModel(branches), tea.WithAltScreen())
+func runTUI(branches []Branch, repo Repo, base string, dryRun, watch bool, sortBy string) error {
+	p := tea.NewProgram(initialModel(branches, repo, base, dryRun, watch, sortBy), tea.WithAltScreen())
 	_, err := p.Run()
 	return err
 }
 
+// refreshBranches re-fetches branches from the repo and re-computes their
+// base status, so a TUI-triggered refresh doesn't lose the ahead/behind,
+// merged and gone columns.
+func (m *model) refreshBranches() error {
+	brs, err := m.repo.RecentBranches()
+	if err != nil {
+		return err
+	}
+	if m.base != "" {
+		annotateBaseStatus(brs, m.repo, m.base)
+	}
+	annotateActivity(brs, m.repo, m.weights)
+	m.branches = brs
+	m.applySortFilter()
+	return nil
+}
+
 // Messages
 
 type tickMsg time.Time
 
 type statusMsg string
 
+// typedConfirmMsg carries the line the user typed to confirm a bulk
+// cleanup that touches unmerged branches; only the exact text "yes"
+// proceeds.
+type typedConfirmMsg string
+
 // Update/View
 
 func (m model) Init() tea.Cmd {
-	return tea.Batch(tick(), status("j/k or ↑/↓ move • / search (fuzzy) • s sort • r refresh • f fetch • enter checkout • x delete • m merge into current • q quit"))
+	cmds := []tea.Cmd{tick(), status("j/k or ↑/↓ move • / search (fuzzy) • space select • V range • a all • c cleanup • D dry-run • s sort • r refresh • f fetch • enter checkout • w worktree path • W add worktree • t watch • x delete • m merge into current • q quit")}
+	if m.watching && m.watcher != nil {
+		cmds = append(cmds, watchCmd(m.watcher))
+	}
+	return tea.Batch(cmds...)
 }
 
 func tick() tea.Cmd {
@@ -79,6 +141,81 @@ func tick() tea.Cmd {
 
 func status(s string) tea.Cmd { return func() tea.Msg { return statusMsg(s) } }
 
+// readTypedConfirm prompts on stdout and blocks for a full line of input,
+// the same way readLine does for search. Anything other than "yes"
+// cancels the pending bulk cleanup.
+func readTypedConfirm() tea.Cmd {
+	return func() tea.Msg {
+		fmt.Print("type 'yes' to confirm, anything else cancels: ")
+		var s string
+		_, _ = fmt.Scanln(&s)
+		return typedConfirmMsg(s)
+	}
+}
+
+func anyUnmerged(branches []Branch) bool {
+	for _, b := range branches {
+		if b.MergedInto == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func splitUpstream(upstream string) (remote, branch string) {
+	parts := strings.SplitN(upstream, "/", 2)
+	if len(parts) != 2 {
+		return "", ""
+	}
+	return parts[0], parts[1]
+}
+
+// runBulk executes (or, in dry-run mode, previews) a cleanup action
+// across targets and returns a human-readable summary.
+func (m *model) runBulk(action string, targets []Branch) string {
+	lines := make([]string, 0, len(targets))
+	for _, b := range targets {
+		switch action {
+		case "delete", "prune-gone":
+			if m.dryRun {
+				lines = append(lines, fmt.Sprintf("git branch -D %s", b.Name))
+				continue
+			}
+			if b.IsCurrent {
+				lines = append(lines, fmt.Sprintf("skip %s: current branch", b.Name))
+				continue
+			}
+			if err := m.repo.DeleteBranch(b.Name); err != nil {
+				lines = append(lines, fmt.Sprintf("%s: failed: %v", b.Name, err))
+			} else {
+				lines = append(lines, fmt.Sprintf("deleted %s", b.Name))
+			}
+		case "push-delete":
+			remote, branchName := splitUpstream(b.Upstream)
+			if remote == "" {
+				lines = append(lines, fmt.Sprintf("skip %s: no upstream", b.Name))
+				continue
+			}
+			if m.dryRun {
+				lines = append(lines, fmt.Sprintf("git push %s --delete %s", remote, branchName))
+				continue
+			}
+			if err := m.repo.DeleteRemoteBranch(remote, branchName); err != nil {
+				lines = append(lines, fmt.Sprintf("%s: failed: %v", b.Name, err))
+			} else {
+				lines = append(lines, fmt.Sprintf("push-deleted %s from %s", branchName, remote))
+			}
+		}
+	}
+	if !m.dryRun {
+		for _, b := range targets {
+			delete(m.selected, b.Name)
+		}
+		_ = m.refreshBranches()
+	}
+	return strings.Join(lines, "; ")
+}
+
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
@@ -88,33 +225,29 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.confirming {
 			switch s {
 			case "y", "Y":
-				if m.cursor >= 0 && m.cursor < len(m.filtered) {
+				if strings.HasPrefix(m.action, "bulk:") {
+					bulkAction := strings.TrimPrefix(m.action, "bulk:")
+					m.status = m.runBulk(bulkAction, m.pendingBulkTargets)
+					m.pendingBulkTargets = nil
+				} else if m.cursor >= 0 && m.cursor < len(m.filtered) {
 					b := m.filtered[m.cursor]
 					if m.action == "delete" {
 						if b.IsCurrent {
 							m.status = "cannot delete current branch"
-						} else if err := gitDeleteBranch(b.Name); err != nil {
+						} else if err := m.repo.DeleteBranch(b.Name); err != nil {
 							m.status = fmt.Sprintf("delete failed: %v", err)
 						} else {
 							m.status = fmt.Sprintf("deleted %s", b.Name)
-							brs, err := getRecentBranches()
-							if err == nil {
-								m.branches = brs
-								m.applySortFilter()
-							}
+							_ = m.refreshBranches()
 						}
 					} else if m.action == "merge" {
 						if b.IsCurrent {
 							m.status = "already on this branch"
-						} else if err := gitMergeIntoCurrent(b.Name); err != nil {
+						} else if err := m.repo.MergeIntoCurrent(b.Name); err != nil {
 							m.status = fmt.Sprintf("merge failed: %v", err)
 						} else {
 							m.status = fmt.Sprintf("merged %s into current", b.Name)
-							brs, err := getRecentBranches()
-							if err == nil {
-								m.branches = brs
-								m.applySortFilter()
-							}
+							_ = m.refreshBranches()
 						}
 					}
 				}
@@ -125,10 +258,27 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.status = "cancelled"
 				m.confirming = false
 				m.action = ""
+				m.pendingBulkTargets = nil
 				return m, nil
 			}
 		}
 
+		// Handle the cleanup submenu opened by "c"
+		if m.cleanupMenu {
+			switch s {
+			case "d":
+				return m.startBulk("delete", m.selectedBranches())
+			case "g":
+				return m.startBulk("prune-gone", goneBranches(m.branches))
+			case "p":
+				return m.startBulk("push-delete", m.selectedBranches())
+			case "esc":
+				m.cleanupMenu = false
+				m.status = "cleanup cancelled"
+			}
+			return m, nil
+		}
+
 		switch s {
 		case "ctrl+c", "q":
 			return m, tea.Quit
@@ -155,12 +305,36 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "enter":
 			if m.cursor >= 0 && m.cursor < len(m.filtered) {
 				b := m.filtered[m.cursor]
-				if err := gitCheckoutBranch(b.Name); err != nil {
+				var checkedOut *ErrBranchCheckedOut
+				if err := m.repo.CheckoutBranch(b.Name); errors.As(err, &checkedOut) {
+					m.status = fmt.Sprintf("%s is checked out at %s (w: print path, W: add worktree here)", b.Name, checkedOut.Path)
+				} else if err != nil {
 					m.status = fmt.Sprintf("checkout failed: %v", err)
 				} else {
 					m.status = fmt.Sprintf("checked out %s", b.Name)
 				}
 			}
+		case "w":
+			if m.cursor >= 0 && m.cursor < len(m.filtered) {
+				b := m.filtered[m.cursor]
+				if b.WorktreePath == "" {
+					m.status = fmt.Sprintf("%s has no linked worktree", b.Name)
+				} else if err := writeCdFile(b.WorktreePath); err != nil {
+					m.status = fmt.Sprintf("write cd file failed: %v", err)
+				} else {
+					m.status = fmt.Sprintf("worktree path written: cd \"$(cat %s)\"", cdFilePath())
+				}
+			}
+		case "W":
+			if m.cursor >= 0 && m.cursor < len(m.filtered) {
+				b := m.filtered[m.cursor]
+				if path, err := m.repo.AddWorktree(b.Name, worktreeBaseDir()); err != nil {
+					m.status = fmt.Sprintf("worktree add failed: %v", err)
+				} else {
+					m.status = fmt.Sprintf("added worktree for %s at %s", b.Name, path)
+					_ = m.refreshBranches()
+				}
+			}
 		case "x", "delete":
 			if m.cursor >= 0 && m.cursor < len(m.filtered) {
 				b := m.filtered[m.cursor]
@@ -175,53 +349,213 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.action = "merge"
 				m.status = fmt.Sprintf("merge %s into current? y/N", b.Name)
 			}
+		case " ":
+			if m.cursor >= 0 && m.cursor < len(m.filtered) {
+				name := m.filtered[m.cursor].Name
+				if m.selected[name] {
+					delete(m.selected, name)
+				} else {
+					m.selected[name] = true
+				}
+			}
+		case "V":
+			if m.rangeAnchor == -1 {
+				m.rangeAnchor = m.cursor
+				m.status = "range select: move cursor, press V again to apply"
+			} else {
+				lo, hi := m.rangeAnchor, m.cursor
+				if lo > hi {
+					lo, hi = hi, lo
+				}
+				for i := lo; i <= hi && i < len(m.filtered); i++ {
+					m.selected[m.filtered[i].Name] = true
+				}
+				m.rangeAnchor = -1
+				m.status = fmt.Sprintf("%d branch(es) selected", len(m.selected))
+			}
+		case "a":
+			for _, b := range m.filtered {
+				m.selected[b.Name] = true
+			}
+			m.status = fmt.Sprintf("%d branch(es) selected", len(m.selected))
+		case "D":
+			m.dryRun = !m.dryRun
+			if m.dryRun {
+				m.status = "dry-run mode on: cleanup will print commands instead of running them"
+			} else {
+				m.status = "dry-run mode off"
+			}
+		case "t":
+			if m.watching {
+				if m.watcher != nil {
+					_ = m.watcher.Close()
+					m.watcher = nil
+				}
+				m.watching = false
+				m.status = "watch mode off"
+			} else {
+				gitDir, err := m.repo.GitDir()
+				if err != nil {
+					m.status = fmt.Sprintf("watch failed: %v", err)
+					return m, nil
+				}
+				w, err := newGitWatcher(gitDir)
+				if err != nil {
+					m.status = fmt.Sprintf("watch failed: %v", err)
+					return m, nil
+				}
+				m.watcher = w
+				m.watching = true
+				m.status = "watch mode on: refreshing on .git changes"
+				return m, watchCmd(m.watcher)
+			}
+		case "c":
+			m.cleanupMenu = true
+			m.status = fmt.Sprintf("cleanup (%d selected): [d]elete selected  [g]one-upstream prune  [p]ush-delete remote  [esc] cancel", len(m.selected))
 		case "r":
-			brs, err := getRecentBranches()
-			if err != nil {
+			if err := m.refreshBranches(); err != nil {
 				m.status = fmt.Sprintf("refresh failed: %v", err)
 				return m, nil
 			}
-			m.branches = brs
-			m.applySortFilter()
 			m.status = "refreshed"
 		case "f":
-			_ = gitFetchAll()
-			brs, err := getRecentBranches()
-			if err != nil {
+			_ = m.repo.FetchAll()
+			if err := m.refreshBranches(); err != nil {
 				m.status = fmt.Sprintf("fetch failed: %v", err)
 				return m, nil
 			}
-			m.branches = brs
-			m.applySortFilter()
 			m.status = "fetched"
 		case "s":
-			// toggle sort: time desc -> name asc -> time asc -> name desc ...
-			if m.sortBy == "time" {
-				m.sortBy = "name"
-				m.sortDesc = false
-			} else if m.sortBy == "name" && !m.sortDesc {
-				m.sortBy = "time"
-				m.sortDesc = false
-			} else if m.sortBy == "time" && !m.sortDesc {
-				m.sortBy = "name"
-				m.sortDesc = true
-			} else {
-				m.sortBy = "time"
-				m.sortDesc = true
+			idx := 0
+			for i, mode := range sortModes {
+				if mode == m.sortBy {
+					idx = i
+					break
+				}
 			}
+			m.sortBy = sortModes[(idx+1)%len(sortModes)]
+			m.status = fmt.Sprintf("sorted by %s", m.sortBy)
 			m.applySortFilter()
 		}
 	case tickMsg:
 		return m, tea.Batch(tick())
 	case statusMsg:
 		m.status = string(msg)
+		m.applySortFilter()
+	case refreshMsg:
+		if err := m.refreshBranches(); err != nil {
+			m.status = fmt.Sprintf("watch refresh failed: %v", err)
+		}
+		if m.watching && m.watcher != nil {
+			return m, watchCmd(m.watcher)
+		}
+	case typedConfirmMsg:
+		bulkAction := strings.TrimPrefix(m.action, "typed:")
+		if string(msg) == "yes" {
+			m.status = m.runBulk(bulkAction, m.pendingBulkTargets)
+		} else {
+			m.status = "cancelled"
+		}
+		m.pendingBulkTargets = nil
+		m.action = ""
+	}
+	return m, nil
+}
+
+// startBulk records the cleanup action and its targets, then either asks
+// for a plain y/N (all targets already merged into base) or requires the
+// typed "yes" confirmation (any target is unmerged).
+func (m model) startBulk(action string, targets []Branch) (tea.Model, tea.Cmd) {
+	m.cleanupMenu = false
+	if len(targets) == 0 {
+		m.status = fmt.Sprintf("no branches to %s", action)
+		return m, nil
+	}
+	m.pendingBulkTargets = targets
+	if anyUnmerged(targets) {
+		m.action = "typed:" + action
+		m.status = fmt.Sprintf("%s %d branch(es), including unmerged ones", action, len(targets))
+		return m, readTypedConfirm()
 	}
+	m.confirming = true
+	m.action = "bulk:" + action
+	m.status = fmt.Sprintf("%s %d branch(es)? y/N", action, len(targets))
 	return m, nil
 }
 
+// selectedBranches returns the currently multi-selected branches in
+// m.branches order.
+func (m model) selectedBranches() []Branch {
+	if len(m.selected) == 0 {
+		return nil
+	}
+	out := make([]Branch, 0, len(m.selected))
+	for _, b := range m.branches {
+		if m.selected[b.Name] {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// cdFilePath is where "w" writes the selected worktree's path, for a shell
+// wrapper function to `cd $(cat ...)` into - grecent itself can't change
+// its parent shell's directory.
+func cdFilePath() string {
+	return filepath.Join(os.TempDir(), "grecent-cd")
+}
+
+func writeCdFile(path string) error {
+	return os.WriteFile(cdFilePath(), []byte(path), 0o644)
+}
+
+// worktreeBaseDir returns the directory new worktrees are created under,
+// overridable via GRECENT_WORKTREE_DIR.
+func worktreeBaseDir() string {
+	if dir := os.Getenv("GRECENT_WORKTREE_DIR"); dir != "" {
+		return dir
+	}
+	return filepath.Join(os.TempDir(), "grecent-worktrees")
+}
+
+func goneBranches(branches []Branch) []Branch {
+	out := make([]Branch, 0)
+	for _, b := range branches {
+		if b.UpstreamGone {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// statusFilters maps ":status" search tokens to a predicate over Branch.
+var statusFilters = map[string]func(Branch) bool{
+	":merged": func(b Branch) bool { return b.MergedInto != "" },
+	":gone":   func(b Branch) bool { return b.UpstreamGone },
+	":ahead":  func(b Branch) bool { return b.Ahead > 0 },
+}
+
 func (m *model) applySortFilter() {
 	m.filtered = m.filtered[:0]
-	if m.search == "" {
+	if expr, ok := strings.CutPrefix(m.search, ":since "); ok {
+		since, err := parseDateExpr(expr)
+		if err != nil {
+			m.status = fmt.Sprintf("since filter: %v", err)
+			m.filtered = append(m.filtered, m.branches...)
+		} else {
+			for _, b := range m.branches {
+				if !b.CommitTime.Before(since) {
+					m.filtered = append(m.filtered, b)
+				}
+			}
+		}
+	} else if pred, ok := statusFilters[m.search]; ok {
+		for _, b := range m.branches {
+			if pred(b) {
+				m.filtered = append(m.filtered, b)
+			}
+		}
+	} else if m.search == "" {
 		m.filtered = append(m.filtered, m.branches...)
 	} else {
 		// Fuzzy filter by branch name, preserving library order
@@ -242,19 +576,7 @@ func (m *model) applySortFilter() {
 
 	// If no active search, apply chosen sort
 	if m.search == "" {
-		sort.SliceStable(m.filtered, func(i, j int) bool {
-			if m.sortBy == "name" {
-				if m.sortDesc {
-					return m.filtered[i].Name > m.filtered[j].Name
-				}
-				return m.filtered[i].Name < m.filtered[j].Name
-			}
-			// sort by time
-			if m.sortDesc {
-				return m.filtered[i].CommitTime.After(m.filtered[j].CommitTime)
-			}
-			return m.filtered[i].CommitTime.Before(m.filtered[j].CommitTime)
-		})
+		sortBranches(m.filtered, m.sortBy)
 	}
 
 	if m.cursor >= len(m.filtered) {
@@ -272,21 +594,35 @@ func (m model) View() string {
 	styleCurrent := lipgloss.NewStyle().Foreground(lipgloss.Color("10")).Bold(true)
 
 	var b strings.Builder
-	b.WriteString(styleTitle.Render("grecent - recent branches") + "\n")
-	b.WriteString(styleHeader.Render("j/k,↑/↓ move • / search (fuzzy) • s sort • r refresh • f fetch • enter checkout • x delete • m merge • q quit") + "\n\n")
+	title := fmt.Sprintf("grecent - recent branches (sort: %s)", m.sortBy)
+	if m.dryRun {
+		title += "  [DRY RUN]"
+	}
+	if m.watching {
+		title += "  [WATCHING]"
+	}
+	if len(m.selected) > 0 {
+		title += fmt.Sprintf("  (%d selected)", len(m.selected))
+	}
+	b.WriteString(styleTitle.Render(title) + "\n")
+	b.WriteString(styleHeader.Render("j/k,↑/↓ move • / search (fuzzy, or :merged :gone :ahead :since <expr>) • space select • V range • a all • c cleanup • D dry-run • s sort • r refresh • f fetch • enter checkout • w worktree path • W add worktree • t watch • x delete • m merge • q quit") + "\n\n")
 	if m.search != "" {
 		b.WriteString(styleHeader.Render("filter: ") + m.search + "\n")
 	}
 
-	// Table header: Branch, Hash, Age, Date, Upstream
-	b.WriteString(styleHeader.Render(fmt.Sprintf("%-2s %-32s %-8s %-8s %-20s %-20s\n", "", "Branch", "Hash", "Age", "Date", "Upstream")))
-	b.WriteString(styleHeader.Render(strings.Repeat("─", 96)) + "\n")
+	// Table header: selection marker, Branch, Hash, Age, Date, Upstream, Status, Worktree
+	b.WriteString(styleHeader.Render(fmt.Sprintf("%-2s %-3s %-32s %-8s %-8s %-20s %-10s %-16s %s\n", "", "", "Branch", "Hash", "Age", "Date", "Upstream", "Status", "Worktree")))
+	b.WriteString(styleHeader.Render(strings.Repeat("─", 112)) + "\n")
 
 	for i, br := range m.filtered {
 		cursor := "  "
 		if i == m.cursor {
 			cursor = styleCursor.Render("→ ")
 		}
+		mark := "[ ]"
+		if m.selected[br.Name] {
+			mark = "[x]"
+		}
 		name := br.Name
 		if br.IsCurrent {
 			name = styleCurrent.Render("* " + name)
@@ -301,7 +637,11 @@ func (m model) View() string {
 		if br.HasUpstream {
 			up = "yes"
 		}
-		row := fmt.Sprintf("%s%-32s %-8s %-8s %-20s %-20s\n", cursor, name, hash, age, date, up)
+		wt := ""
+		if br.WorktreePath != "" {
+			wt = "wt: " + br.WorktreePath
+		}
+		row := fmt.Sprintf("%s%-3s %-32s %-8s %-8s %-20s %-10s %-16s %s\n", cursor, mark, name, hash, age, date, up, baseStatus(br), wt)
 		b.WriteString(row)
 	}
 
@@ -322,38 +662,3 @@ func readLine(target *string) tea.Cmd {
 		return statusMsg("filter applied")
 	}
 }
-
-func gitCheckoutBranch(name string) error {
-	return runCmdSilent("git", "checkout", name)
-}
-
-func gitDeleteBranch(name string) error {
-	// Try safe delete; if it fails, attempt force delete
-	if err := runCmdSilent("git", "branch", "-d", name); err != nil {
-		return runCmdSilent("git", "branch", "-D", name)
-	}
-	return nil
-}
-
-func gitMergeIntoCurrent(name string) error {
-	// Merge selected branch into current HEAD
-	return runCmdSilent("git", "merge", name)
-}
-
-func runCmdSilent(name string, args ...string) error {
-	cmd := newCommand(name, args...)
-	return cmd.Run()
-}
-
-// newCommand wraps exec.Command for testing/mocking if needed
-func newCommand(name string, args ...string) *exec.Cmd {
-	return execCommand(name, args...)
-}
-
-var execCommand = func(name string, args ...string) *exec.Cmd {
-	return osCommand(name, args...)
-}
-
-var osCommand = func(name string, args ...string) *exec.Cmd {
-	return exec.Command(name, args...)
-}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long we wait after the last observed change before
+// refreshing - a single git command (e.g. a rebase) can touch HEAD, several
+// refs and the reflog in quick succession, and we want one refresh out of
+// that, not several.
+const watchDebounce = 200 * time.Millisecond
+
+// watchFiles lists the single .git files whose changes mean the branch
+// list may be stale: HEAD moves and packed-refs compaction.
+func watchFiles(gitDir string) []string {
+	return []string{
+		filepath.Join(gitDir, "HEAD"),
+		filepath.Join(gitDir, "packed-refs"),
+		filepath.Join(gitDir, "logs", "HEAD"),
+	}
+}
+
+// watchTrees lists the .git directories whose changes mean the branch list
+// may be stale: ref and reflog writes, including ones for branches with
+// hierarchical names (e.g. "feature/x") that live in a subdirectory of
+// these rather than directly inside them.
+func watchTrees(gitDir string) []string {
+	return []string{
+		filepath.Join(gitDir, "refs", "heads"),
+		filepath.Join(gitDir, "logs", "refs", "heads"),
+	}
+}
+
+// newGitWatcher opens an fsnotify watcher on gitDir's refs and reflogs.
+// Paths that don't exist yet (packed-refs before the first gc, logs/
+// before the first reflog entry) are added best-effort and simply won't
+// fire. fsnotify isn't recursive, so a watch on refs/heads only reports
+// the subdirectory itself being created, not later writes to files inside
+// it - addTree walks every existing subdirectory up front, and watchCmd
+// extends the watch to any new ones as they're created, so hierarchical
+// branch names stay covered as they appear.
+func newGitWatcher(gitDir string) (*fsnotify.Watcher, error) {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range watchFiles(gitDir) {
+		_ = w.Add(p)
+	}
+	for _, dir := range watchTrees(gitDir) {
+		addTree(w, dir)
+	}
+	return w, nil
+}
+
+// addTree adds a watch on root and every directory beneath it, best-effort.
+func addTree(w *fsnotify.Watcher, root string) {
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			_ = w.Add(path)
+		}
+		return nil
+	})
+}
+
+// refreshMsg tells the model a watched .git path changed and the branch
+// list should be recomputed.
+type refreshMsg struct{}
+
+// watchCmd blocks until w reports a change, debounces a burst of events
+// into one, and emits refreshMsg. The model re-issues watchCmd after every
+// refreshMsg to keep watching for as long as watch mode stays on.
+func watchCmd(w *fsnotify.Watcher) tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case ev, ok := <-w.Events:
+			if !ok {
+				return nil
+			}
+			trackNewDir(w, ev)
+		case _, ok := <-w.Errors:
+			if !ok {
+				return nil
+			}
+		}
+		debounce := time.NewTimer(watchDebounce)
+		defer debounce.Stop()
+		for {
+			select {
+			case ev, ok := <-w.Events:
+				if !ok {
+					return refreshMsg{}
+				}
+				trackNewDir(w, ev)
+				if !debounce.Stop() {
+					<-debounce.C
+				}
+				debounce.Reset(watchDebounce)
+			case <-debounce.C:
+				return refreshMsg{}
+			}
+		}
+	}
+}
+
+// trackNewDir extends the watch to ev's path (and anything already inside
+// it) when ev reports a newly created directory, so a freshly created
+// refs/heads/feature or logs/refs/heads/feature keeps being watched for
+// the individual branch writes that follow it.
+func trackNewDir(w *fsnotify.Watcher, ev fsnotify.Event) {
+	if ev.Op&fsnotify.Create == 0 {
+		return
+	}
+	if info, err := os.Stat(ev.Name); err == nil && info.IsDir() {
+		addTree(w, ev.Name)
+	}
+}